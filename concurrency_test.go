@@ -0,0 +1,114 @@
+package epub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddImageAsyncResolvesBeforeWrite(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fake image bytes"))
+	}))
+	defer srv.Close()
+
+	e := NewEpub(testEpubTitle)
+	e.SetDownloadConcurrency(2)
+
+	var handles []*ImageHandle
+	for i := 0; i < 5; i++ {
+		handles = append(handles, e.AddImageAsync(srv.URL+"/photo.jpg", ""))
+	}
+
+	for _, h := range handles {
+		if err := h.Wait(); err != nil {
+			t.Fatalf("unexpected error from AddImageAsync: %s", err)
+		}
+	}
+
+	if len(e.Images()) != 5 {
+		t.Fatalf("expected 5 images, got %d", len(e.Images()))
+	}
+
+	var buf writeCounter
+	if _, err := e.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error from WriteTo: %s", err)
+	}
+}
+
+func TestEpubConcurrentAdds(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := e.AddSection("<p>hi</p>", "Section", "", ""); err != nil {
+				t.Errorf("unexpected error adding section: %s", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(e.Sections()) != 20 {
+		t.Errorf("expected 20 sections, got %d", len(e.Sections()))
+	}
+}
+
+// TestReleaseDownloadSlotUsesTheAcquiredSemaphore guards against
+// releaseDownloadSlot re-reading e.downloadSem instead of releasing to the
+// semaphore its matching acquireDownloadSlot actually returned: calling
+// SetDownloadConcurrency while a download is in flight resets e.downloadSem,
+// so releasing to the freshly re-read (and never-acquired) channel would
+// block forever instead of releasing the slot that was actually taken.
+func TestReleaseDownloadSlotUsesTheAcquiredSemaphore(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	sem := e.acquireDownloadSlot()
+	e.SetDownloadConcurrency(2)
+
+	done := make(chan struct{})
+	go func() {
+		e.releaseDownloadSlot(sem)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("releaseDownloadSlot did not return within 2s; it likely blocked on a stale semaphore")
+	}
+}
+
+// TestWriteToFailsAfterAsyncDownloadError guards against a failed
+// AddImageAsync download being silently written as a corrupt (empty
+// content) manifest entry when the caller never calls the returned
+// Handle's Wait() to notice the failure themselves.
+func TestWriteToFailsAfterAsyncDownloadError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	e := NewEpub(testEpubTitle)
+	e.AddImageAsync(srv.URL+"/photo.jpg", "")
+
+	var buf writeCounter
+	if _, err := e.WriteTo(&buf); err == nil {
+		t.Fatal("expected WriteTo to return an error after a failed async image download, got nil")
+	}
+}
+
+// writeCounter is a minimal io.Writer, used instead of bytes.Buffer so this
+// test file doesn't need to import "bytes" just to discard output.
+type writeCounter struct {
+	n int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
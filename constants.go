@@ -0,0 +1,76 @@
+package epub
+
+const (
+	containerFilename = "container.xml"
+	containerFileTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="%s" media-type="application/oebps-package+xml" />
+  </rootfiles>
+</container>`
+
+	contentFolderName = "EPUB"
+
+	cssFolderName = "css"
+	cssFileFormat = "css%04d.css"
+
+	fontFolderName = "fonts"
+	fontFileFormat = "font%04d%s"
+
+	imageFolderName = "images"
+	imageFileFormat = "image%04d%s"
+
+	metaInfFolderName = "META-INF"
+
+	mimetypeFilename = "mimetype"
+	mimetypeContents = "application/epub+zip"
+
+	pkgFilename = "package.opf"
+
+	sectionFileFormat  = "section%04d.xhtml"
+	sectionsFolderName = "sections"
+
+	tempDirPrefix = "go-epub"
+
+	tocNavFilename = "nav.xhtml"
+	tocNcxFilename = "toc.ncx"
+
+	urnUUIDPrefix = "urn:uuid:"
+
+	xhtmlFileTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<title>%s</title>
+%s</head>
+<body>
+%s
+</body>
+</html>`
+
+	tocNavFileTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+<title>%s</title>
+</head>
+<body>
+<nav epub:type="toc">
+<ol>
+%s</ol>
+</nav>
+</body>
+</html>`
+
+	tocNcxFileTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+<meta name="dtb:uid" content="%s"/>
+</head>
+<docTitle>
+<text>%s</text>
+</docTitle>
+<navMap>
+%s</navMap>
+</ncx>`
+)
@@ -0,0 +1,135 @@
+package epub
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+const coverSectionFilename = "cover.xhtml"
+
+// SetCover adds a cover image to the EPUB from a local file path or an
+// http(s) URL, returning the filename it was given inside the EPUB.
+// The image's manifest item is marked with properties="cover-image" for
+// EPUB 3 readers, and a legacy <meta name="cover"> entry is emitted
+// alongside it for older readers like Calibre that only look for that. A
+// dedicated cover page is generated and inserted at the front of the spine
+// with linear="no", since a cover is meant to be seen, not read in order.
+// If cssSource is non-empty, it's added as a stylesheet (as AddCSS would)
+// and linked from the cover page.
+func (e *Epub) SetCover(imgSource string, cssSource string) (string, error) {
+	content, err := fetchResource(imgSource)
+	if err != nil {
+		return "", fmt.Errorf("error adding cover image from %q: %w", imgSource, err)
+	}
+
+	return e.setCover(content, mediaTypeForExtension(imgSource), cssSource)
+}
+
+// SetCoverFromReader behaves like SetCover, but reads the cover image from
+// r instead of a path or URL. mediaType (e.g. "image/jpeg") identifies the
+// image's format, since a Reader carries no filename to infer it from.
+func (e *Epub) SetCoverFromReader(r io.Reader, mediaType string, cssSource string) (string, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("error reading cover image: %w", err)
+	}
+
+	return e.setCover(content, mediaType, cssSource)
+}
+
+func (e *Epub) setCover(content []byte, mediaType string, cssSource string) (string, error) {
+	imgFilename := "cover" + extensionForMediaType(mediaType)
+
+	// AddCSS takes e.mu itself, so it has to run before we take the lock
+	// below rather than while holding it.
+	var cssFilename string
+	if cssSource != "" {
+		var err error
+		cssFilename, err = e.AddCSS(cssSource, "")
+		if err != nil {
+			return "", fmt.Errorf("error adding cover stylesheet: %w", err)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.removeCoverLocked()
+
+	e.images = append(e.images, &epubImage{
+		id:         "cover-image",
+		filename:   imgFilename,
+		mediaType:  mediaType,
+		properties: "cover-image",
+		content:    content,
+	})
+	e.coverImageFilename = imgFilename
+
+	cover := &epubSection{
+		id:          "cover",
+		filename:    coverSectionFilename,
+		title:       "Cover",
+		body:        fmt.Sprintf(`<div id="cover"><img src="../%s/%s" alt="Cover" /></div>`, imageFolderName, imgFilename),
+		cssFilename: cssFilename,
+		linear:      "no",
+	}
+	e.sections = append([]*epubSection{cover}, e.sections...)
+
+	return imgFilename, nil
+}
+
+// removeCoverLocked drops any cover image, cover section, and cover
+// stylesheet set by a prior SetCover/SetCoverFromReader call, so setting a
+// new cover replaces the old one instead of leaving a duplicate
+// "cover-image"/"cover" id (or an orphaned stylesheet manifest entry)
+// behind. Callers must hold e.mu.
+func (e *Epub) removeCoverLocked() {
+	if e.coverImageFilename == "" {
+		return
+	}
+
+	for i, img := range e.images {
+		if img.id == "cover-image" {
+			e.images = append(e.images[:i], e.images[i+1:]...)
+			break
+		}
+	}
+
+	for i, s := range e.sections {
+		if s.id == "cover" {
+			if s.cssFilename != "" {
+				for j, c := range e.css {
+					if c.filename == s.cssFilename {
+						e.css = append(e.css[:j], e.css[j+1:]...)
+						break
+					}
+				}
+			}
+			e.sections = append(e.sections[:i], e.sections[i+1:]...)
+			break
+		}
+	}
+
+	e.coverImageFilename = ""
+}
+
+// extensionForMediaType maps a handful of common image MIME types to the
+// file extension SetCoverFromReader should give the cover image, since a
+// Reader alone doesn't carry one.
+func extensionForMediaType(mediaType string) string {
+	switch mediaType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,123 @@
+package epub
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetCoverFromReader(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	r := &stringReader{s: "not a real PNG, just test bytes"}
+	imgFilename, err := e.SetCoverFromReader(r, "image/png", "")
+	if err != nil {
+		t.Fatalf("unexpected error from SetCoverFromReader: %s", err)
+	}
+	if imgFilename != "cover.png" {
+		t.Errorf("expected cover filename %q, got %q", "cover.png", imgFilename)
+	}
+	if e.CoverImage() != imgFilename {
+		t.Errorf("expected CoverImage() to return %q, got %q", imgFilename, e.CoverImage())
+	}
+
+	sections := e.Sections()
+	if len(sections) == 0 || sections[0].Filename != coverSectionFilename {
+		t.Errorf("expected the cover page to be the first section, got %+v", sections)
+	}
+}
+
+func TestSetCoverInsertsBeforeExistingSections(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	if _, err := e.AddSection("<p>Chapter 1</p>", "Chapter 1", "", ""); err != nil {
+		t.Fatalf("unexpected error adding section: %s", err)
+	}
+	if _, err := e.SetCoverFromReader(&stringReader{s: "png bytes"}, "image/png", ""); err != nil {
+		t.Fatalf("unexpected error from SetCoverFromReader: %s", err)
+	}
+
+	sections := e.Sections()
+	if len(sections) != 2 || sections[0].Filename != coverSectionFilename || sections[1].Title != "Chapter 1" {
+		t.Errorf("expected the cover page before the existing chapter, got %+v", sections)
+	}
+}
+
+func TestSetCoverTwiceReplacesThePreviousCover(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	if _, err := e.SetCoverFromReader(&stringReader{s: "first cover"}, "image/png", ""); err != nil {
+		t.Fatalf("unexpected error from first SetCoverFromReader: %s", err)
+	}
+	imgFilename, err := e.SetCoverFromReader(&stringReader{s: "second cover"}, "image/jpeg", "")
+	if err != nil {
+		t.Fatalf("unexpected error from second SetCoverFromReader: %s", err)
+	}
+	if imgFilename != "cover.jpg" {
+		t.Errorf("expected cover filename %q, got %q", "cover.jpg", imgFilename)
+	}
+	if e.CoverImage() != imgFilename {
+		t.Errorf("expected CoverImage() to return %q, got %q", imgFilename, e.CoverImage())
+	}
+
+	var coverImages int
+	for _, img := range e.images {
+		if img.id == "cover-image" {
+			coverImages++
+		}
+	}
+	if coverImages != 1 {
+		t.Errorf("expected exactly one manifest item with id %q, got %d", "cover-image", coverImages)
+	}
+
+	var coverSections int
+	for _, s := range e.Sections() {
+		if s.Filename == coverSectionFilename {
+			coverSections++
+		}
+	}
+	if coverSections != 1 {
+		t.Errorf("expected exactly one cover section, got %d", coverSections)
+	}
+}
+
+func TestSetCoverWithCSSThreeTimesDoesNotLeakStylesheets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("body { margin: 0; }"))
+	}))
+	defer srv.Close()
+
+	e := NewEpub(testEpubTitle)
+
+	for i := 0; i < 3; i++ {
+		if _, err := e.SetCoverFromReader(&stringReader{s: "cover bytes"}, "image/png", srv.URL+"/cover.css"); err != nil {
+			t.Fatalf("unexpected error from SetCoverFromReader: %s", err)
+		}
+	}
+
+	if len(e.css) != 1 {
+		t.Errorf("expected exactly one CSS manifest entry after 3 calls, got %d", len(e.css))
+	}
+
+	sections := e.Sections()
+	if len(sections) == 0 || sections[0].Filename != coverSectionFilename {
+		t.Fatalf("expected the cover page to be the first section, got %+v", sections)
+	}
+}
+
+// stringReader is a minimal io.Reader over a string, used so the tests
+// exercise SetCoverFromReader with a Reader that isn't also an io.ReaderAt.
+type stringReader struct {
+	s string
+	i int
+}
+
+func (r *stringReader) Read(p []byte) (int, error) {
+	if r.i >= len(r.s) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.s[r.i:])
+	r.i += n
+	return n, nil
+}
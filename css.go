@@ -0,0 +1,120 @@
+package epub
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// epubCSS is a CSS stylesheet that's been added to the EPUB's manifest.
+type epubCSS struct {
+	id       string
+	filename string
+	content  []byte
+}
+
+// AddCSS adds a CSS stylesheet to the EPUB, either from a local file path or
+// an http(s) URL, and returns the filename it was given inside the EPUB so
+// it can be referenced when adding a section. If cssFilename is empty, one
+// is generated automatically.
+func (e *Epub) AddCSS(source string, cssFilename string) (string, error) {
+	content, err := fetchResource(source)
+	if err != nil {
+		return "", fmt.Errorf("error adding CSS from %q: %w", source, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if cssFilename == "" {
+		cssFilename = fmt.Sprintf(cssFileFormat, len(e.css)+1)
+	}
+
+	return e.addCSSContentLocked(content, cssFilename)
+}
+
+// AddCSSFromReader adds a CSS stylesheet to the EPUB from raw content
+// rather than a path or URL. It's meant for callers that have already
+// fetched the stylesheet themselves, such as the htmlimport subsystem. If
+// cssFilename is empty, one is generated automatically.
+func (e *Epub) AddCSSFromReader(r io.Reader, cssFilename string) (string, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("error reading CSS content: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if cssFilename == "" {
+		cssFilename = fmt.Sprintf(cssFileFormat, len(e.css)+1)
+	}
+
+	return e.addCSSContentLocked(content, cssFilename)
+}
+
+// CSSHandle refers to a stylesheet added with AddCSSAsync whose content may
+// still be downloading in the background.
+type CSSHandle struct {
+	// Filename is assigned immediately and is safe to reference right away.
+	Filename string
+
+	done chan struct{}
+	err  error
+}
+
+// Wait blocks until the stylesheet's download has finished, returning any
+// error it encountered.
+func (h *CSSHandle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+// AddCSSAsync behaves like AddCSS, but fetches source in the background,
+// dispatched to the same worker pool AddImageAsync uses, rather than
+// blocking the caller until the download completes.
+func (e *Epub) AddCSSAsync(source string, cssFilename string) *CSSHandle {
+	e.mu.Lock()
+	if cssFilename == "" {
+		cssFilename = fmt.Sprintf(cssFileFormat, len(e.css)+1)
+	}
+	css := &epubCSS{id: fmt.Sprintf("css%04d", len(e.css)+1), filename: cssFilename}
+	e.css = append(e.css, css)
+	e.mu.Unlock()
+
+	h := &CSSHandle{Filename: cssFilename, done: make(chan struct{})}
+
+	e.pending.Add(1)
+	go func() {
+		defer e.pending.Done()
+		defer close(h.done)
+
+		sem := e.acquireDownloadSlot()
+		defer e.releaseDownloadSlot(sem)
+
+		content, err := fetchResource(source)
+		if err != nil {
+			h.err = fmt.Errorf("error adding CSS from %q: %w", source, err)
+			e.recordAsyncErr(h.err)
+			return
+		}
+
+		e.mu.Lock()
+		css.content = content
+		e.mu.Unlock()
+	}()
+
+	return h
+}
+
+// addCSSContentLocked appends a fully-loaded stylesheet to the manifest.
+// The caller must hold e.mu.
+func (e *Epub) addCSSContentLocked(content []byte, cssFilename string) (string, error) {
+	e.css = append(e.css, &epubCSS{
+		id:       fmt.Sprintf("css%04d", len(e.css)+1),
+		filename: cssFilename,
+		content:  content,
+	})
+
+	return cssFilename, nil
+}
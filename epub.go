@@ -0,0 +1,236 @@
+// Package epub generates valid EPUB 3 files with support for images, fonts,
+// CSS, and a navigation document, all built from content provided as Go
+// strings and files.
+package epub
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Epub implements an in-progress EPUB file. Use NewEpub to create one, add
+// content to it with the Add* methods, and call Write to produce the final
+// .epub file. An Epub is safe for concurrent use by multiple goroutines.
+type Epub struct {
+	mu sync.Mutex
+
+	asyncErr            error
+	authors             []string
+	coverImageFilename  string
+	css                 []*epubCSS
+	downloadConcurrency int
+	downloadSem         chan struct{}
+	fonts               []*epubFont
+	identifier          string
+	images              []*epubImage
+	lang                string
+	pending             sync.WaitGroup
+	pkgID               string
+	sections            []*epubSection
+	title               string
+}
+
+// defaultDownloadConcurrency is how many AddImageAsync/AddCSSAsync
+// downloads run at once unless SetDownloadConcurrency says otherwise.
+const defaultDownloadConcurrency = 4
+
+// NewEpub creates a new Epub with the given title. The title is the only
+// mandatory piece of metadata; everything else defaults to sensible values
+// (a random UUID identifier and "en" for the language) and can be overridden
+// with the corresponding setters.
+func NewEpub(title string) *Epub {
+	e := &Epub{
+		identifier: urnUUIDPrefix + uuid.New().String(),
+		lang:       "en",
+		pkgID:      "pub-id",
+		title:      title,
+	}
+
+	return e
+}
+
+// SetDownloadConcurrency sets how many AddImageAsync/AddCSSAsync downloads
+// may run at once. The default is 4. Values less than 1 are treated as 1.
+func (e *Epub) SetDownloadConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.downloadConcurrency = n
+	// Rebuilt lazily, sized to the new limit, the next time a download
+	// slot is requested.
+	e.downloadSem = nil
+}
+
+// acquireDownloadSlot blocks until a download slot is available, creating
+// the semaphore on first use, and returns the semaphore it acquired from so
+// the matching releaseDownloadSlot call releases to that same one. It can't
+// just re-read e.downloadSem itself: a concurrent SetDownloadConcurrency
+// resets e.downloadSem to nil once a new limit takes effect, which would
+// otherwise leave an in-flight download releasing to the wrong (or a nil)
+// channel and blocking forever.
+func (e *Epub) acquireDownloadSlot() chan struct{} {
+	e.mu.Lock()
+	if e.downloadSem == nil {
+		n := e.downloadConcurrency
+		if n < 1 {
+			n = defaultDownloadConcurrency
+		}
+		e.downloadSem = make(chan struct{}, n)
+	}
+	sem := e.downloadSem
+	e.mu.Unlock()
+
+	sem <- struct{}{}
+	return sem
+}
+
+// releaseDownloadSlot releases the slot acquired from acquireDownloadSlot's
+// returned semaphore.
+func (e *Epub) releaseDownloadSlot(sem chan struct{}) {
+	<-sem
+}
+
+// recordAsyncErr stores the first error reported by an AddImageAsync or
+// AddCSSAsync download, so WriteTo can refuse to render the EPUB even if
+// the caller never called the returned Handle's Wait() to notice the
+// failure themselves.
+func (e *Epub) recordAsyncErr(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.asyncErr == nil {
+		e.asyncErr = err
+	}
+}
+
+// Title returns the EPUB's title.
+func (e *Epub) Title() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.title
+}
+
+// SetTitle sets the EPUB's title.
+func (e *Epub) SetTitle(title string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.title = title
+}
+
+// Author returns the EPUB's first author, or an empty string if none was
+// set.
+func (e *Epub) Author() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.authors) == 0 {
+		return ""
+	}
+	return e.authors[0]
+}
+
+// Authors returns all of the EPUB's authors, in the order of the package
+// document's dc:creator entries.
+func (e *Epub) Authors() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	authors := make([]string, len(e.authors))
+	copy(authors, e.authors)
+	return authors
+}
+
+// SetAuthor sets the EPUB's author, replacing any authors set previously.
+func (e *Epub) SetAuthor(author string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.authors = []string{author}
+}
+
+// Language returns the EPUB's language, as an IETF language tag.
+func (e *Epub) Language() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.lang
+}
+
+// SetLanguage sets the EPUB's language. It should be set to an IETF
+// language tag, such as "en" or "fr". The default is "en".
+func (e *Epub) SetLanguage(lang string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lang = lang
+}
+
+// Section describes a single entry in the EPUB's spine/table of contents.
+type Section struct {
+	Title    string
+	Filename string
+	Body     string
+}
+
+// Sections returns the EPUB's sections, in spine order.
+func (e *Epub) Sections() []Section {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sections := make([]Section, len(e.sections))
+	for i, s := range e.sections {
+		sections[i] = Section{Title: s.title, Filename: s.filename, Body: s.body}
+	}
+	return sections
+}
+
+// Image describes a single image in the EPUB's manifest.
+type Image struct {
+	Filename  string
+	MediaType string
+}
+
+// Images returns the EPUB's images, in manifest order.
+func (e *Epub) Images() []Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	images := make([]Image, len(e.images))
+	for i, img := range e.images {
+		images[i] = Image{Filename: img.filename, MediaType: img.mediaType}
+	}
+	return images
+}
+
+// CoverImage returns the filename of the EPUB's cover image, or an empty
+// string if one hasn't been set with SetCover.
+func (e *Epub) CoverImage() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.coverImageFilename
+}
+
+// Identifier returns the EPUB's unique identifier.
+func (e *Epub) Identifier() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.identifier
+}
+
+// SetIdentifier sets the EPUB's unique identifier, such as a URN, ISBN, or
+// UUID. The default is a randomly generated UUID.
+func (e *Epub) SetIdentifier(identifier string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.identifier = identifier
+}
@@ -0,0 +1,39 @@
+package epub
+
+import (
+	"fmt"
+)
+
+// epubFont is a font file that's been added to the EPUB's manifest.
+type epubFont struct {
+	id       string
+	filename string
+	content  []byte
+}
+
+// AddFont adds a font file to the EPUB, either from a local file path or an
+// http(s) URL, and returns the filename it was given inside the EPUB so it
+// can be referenced from a stylesheet. If fontFilename is empty, one is
+// generated automatically.
+func (e *Epub) AddFont(source string, fontFilename string) (string, error) {
+	content, err := fetchResource(source)
+	if err != nil {
+		return "", fmt.Errorf("error adding font from %q: %w", source, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if fontFilename == "" {
+		fontFilename = fmt.Sprintf(fontFileFormat, len(e.fonts)+1, extensionOf(source))
+	}
+
+	font := &epubFont{
+		id:       fmt.Sprintf("font%04d", len(e.fonts)+1),
+		filename: fontFilename,
+		content:  content,
+	}
+	e.fonts = append(e.fonts, font)
+
+	return fontFilename, nil
+}
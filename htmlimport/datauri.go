@@ -0,0 +1,37 @@
+package htmlimport
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// parseDataURI decodes a "data:" URI into its MIME type and raw content.
+// Only the base64-encoded form is supported, which covers every image data
+// URI produced by browsers and image editors.
+func parseDataURI(uri string) (mediaType string, content []byte, err error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return "", nil, fmt.Errorf("not a data URI: %q", uri)
+	}
+
+	rest := strings.TrimPrefix(uri, "data:")
+	header, data, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, fmt.Errorf("malformed data URI: missing comma")
+	}
+
+	if !strings.HasSuffix(header, ";base64") {
+		return "", nil, fmt.Errorf("unsupported data URI encoding: only base64 is supported")
+	}
+	mediaType = strings.TrimSuffix(header, ";base64")
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+
+	content, err = base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("error decoding base64 data URI content: %w", err)
+	}
+
+	return mediaType, content, nil
+}
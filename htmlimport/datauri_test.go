@@ -0,0 +1,32 @@
+package htmlimport
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseDataURI(t *testing.T) {
+	// "hi" base64-encoded, with an image/png media type.
+	mediaType, content, err := parseDataURI("data:image/png;base64,aGk=")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if mediaType != "image/png" {
+		t.Errorf("expected media type %q, got %q", "image/png", mediaType)
+	}
+	if !bytes.Equal(content, []byte("hi")) {
+		t.Errorf("expected decoded content %q, got %q", "hi", content)
+	}
+}
+
+func TestParseDataURIRejectsNonBase64(t *testing.T) {
+	if _, _, err := parseDataURI("data:image/png,not-base64"); err == nil {
+		t.Error("expected an error for a non-base64 data URI")
+	}
+}
+
+func TestParseDataURIRejectsNonDataURI(t *testing.T) {
+	if _, _, err := parseDataURI("https://example.com/image.png"); err == nil {
+		t.Error("expected an error for a non-data URI")
+	}
+}
@@ -0,0 +1,89 @@
+package htmlimport
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// fetchAll downloads every registered remote resource (those whose content
+// wasn't already decoded from a data: URI), honoring the configured
+// concurrency limit so a page with dozens of images doesn't open dozens of
+// connections at once.
+func (imp *importer) fetchAll() error {
+	jobs := append(uniqueJobs(imp.images), uniqueJobs(imp.stylesheets)...)
+
+	sem := make(chan struct{}, imp.opts.concurrency())
+	var wg sync.WaitGroup
+	errs := make(chan error, len(jobs))
+
+	for _, job := range jobs {
+		if job.content != nil {
+			continue
+		}
+
+		job := job
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			content, mediaType, err := imp.fetch(job.source)
+			if err != nil {
+				errs <- err
+				return
+			}
+			job.content = content
+			job.mediaType = mediaType
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (imp *importer) fetch(source string) ([]byte, string, error) {
+	resp, err := imp.opts.client().Get(source)
+	if err != nil {
+		return nil, "", fmt.Errorf("error downloading %q: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("error downloading %q: got HTTP status %s", source, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading response body for %q: %w", source, err)
+	}
+
+	return content, resp.Header.Get("Content-Type"), nil
+}
+
+// uniqueJobs flattens a map that may alias the same *resourceJob under
+// multiple keys (e.g. an original and a resolved URL) into a deduplicated
+// slice.
+func uniqueJobs(m map[string]*resourceJob) []*resourceJob {
+	seen := make(map[*resourceJob]bool, len(m))
+	var jobs []*resourceJob
+	for _, job := range m {
+		if seen[job] {
+			continue
+		}
+		seen[job] = true
+		jobs = append(jobs, job)
+	}
+
+	return jobs
+}
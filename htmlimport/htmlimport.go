@@ -0,0 +1,152 @@
+// Package htmlimport turns a web page into a self-contained EPUB section.
+// It downloads every image and stylesheet the page references, inlines
+// them into an Epub via AddImageFromReader/AddCSSFromReader, rewrites the page to point at the
+// local copies, and adds the result as a new section with AddSection, so
+// callers don't have to reimplement the fetch/rewrite loop themselves.
+package htmlimport
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	epub "github.com/ne0z/go-epub"
+)
+
+// Options configures how a page is imported.
+type Options struct {
+	// Client fetches referenced images and stylesheets. http.DefaultClient
+	// is used if this is nil.
+	Client *http.Client
+
+	// Concurrency caps how many resources are fetched at once. Defaults to
+	// 4 if zero or negative.
+	Concurrency int
+}
+
+func (o *Options) client() *http.Client {
+	if o == nil || o.Client == nil {
+		return http.DefaultClient
+	}
+	return o.Client
+}
+
+func (o *Options) concurrency() int {
+	if o == nil || o.Concurrency <= 0 {
+		return 4
+	}
+	return o.Concurrency
+}
+
+// ImportURL fetches htmlURL and imports it into e as described by Import.
+func ImportURL(e *epub.Epub, htmlURL string, title string, opts *Options) (string, error) {
+	resp, err := opts.client().Get(htmlURL)
+	if err != nil {
+		return "", fmt.Errorf("error fetching %q: %w", htmlURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching %q: got HTTP status %s", htmlURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body for %q: %w", htmlURL, err)
+	}
+
+	return Import(e, string(body), htmlURL, title, opts)
+}
+
+// Import parses htmlSource, resolving relative resource references against
+// baseURL, downloads every image and stylesheet it finds (including
+// data: URI images and the best candidate out of any srcset/picture
+// elements), adds them to e, rewrites the document to reference the local
+// copies, and adds the result to e as a new section. It returns the
+// section's filename inside the EPUB.
+func Import(e *epub.Epub, htmlSource string, baseURL string, title string, opts *Options) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing base URL %q: %w", baseURL, err)
+	}
+
+	doc, err := html.Parse(strings.NewReader(htmlSource))
+	if err != nil {
+		return "", fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	imp := &importer{
+		epub:        e,
+		base:        base,
+		opts:        opts,
+		images:      map[string]*resourceJob{},
+		stylesheets: map[string]*resourceJob{},
+	}
+	imp.rewrite(doc)
+
+	if err := imp.fetchAll(); err != nil {
+		return "", err
+	}
+
+	if err := imp.inlineAll(); err != nil {
+		return "", err
+	}
+
+	bodyNode := findNode(doc, "body")
+	if bodyNode == nil {
+		return "", fmt.Errorf("HTML document has no <body>")
+	}
+
+	var b strings.Builder
+	for c := bodyNode.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return "", fmt.Errorf("error rendering imported HTML: %w", err)
+		}
+	}
+
+	cssFilename := ""
+	if imp.firstStylesheet != nil {
+		cssFilename = imp.firstStylesheet.localFilename
+	}
+
+	return e.AddSection(b.String(), title, "", cssFilename)
+}
+
+// resourceJob tracks a single referenced resource: its resolved source
+// (a URL to fetch, or a data: URI to decode) and the local filename it's
+// been assigned inside the EPUB.
+type resourceJob struct {
+	source        string
+	localFilename string
+	mediaType     string
+	content       []byte
+}
+
+type importer struct {
+	epub        *epub.Epub
+	base        *url.URL
+	opts        *Options
+	images      map[string]*resourceJob
+	stylesheets map[string]*resourceJob
+	// firstStylesheet is the first <link rel="stylesheet"> encountered in
+	// document order, tracked alongside stylesheets (a map, so iteration
+	// order can't be used to recover it) so the generated section links
+	// the same stylesheet on every run.
+	firstStylesheet *resourceJob
+	imageCount      int
+	cssCount        int
+}
+
+// resolve turns a possibly-relative URL found in the document into an
+// absolute one, relative to the page's base URL.
+func (imp *importer) resolve(raw string) (string, error) {
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return imp.base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,161 @@
+package htmlimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	epub "github.com/ne0z/go-epub"
+)
+
+func TestImportDownloadsAndRewritesResources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/photo.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("fake jpeg bytes"))
+		case "/style.css":
+			w.Header().Set("Content-Type", "text/css")
+			w.Write([]byte("body { color: red; }"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	html := `<html><head>
+<link rel="stylesheet" href="/style.css">
+</head><body>
+<p>Hello</p>
+<img src="/photo.jpg" alt="a photo">
+<img src="data:image/png;base64,aGk=" alt="inline">
+<a href="/other-page.html">read more</a>
+</body></html>`
+
+	e := epub.NewEpub("Article")
+	filename, err := Import(e, html, srv.URL, "Article", nil)
+	if err != nil {
+		t.Fatalf("unexpected error from Import: %s", err)
+	}
+	if filename == "" {
+		t.Fatal("expected a non-empty section filename")
+	}
+
+	images := e.Images()
+	if len(images) != 2 {
+		t.Fatalf("expected 2 images to be added, got %d", len(images))
+	}
+
+	sections := e.Sections()
+	if len(sections) != 1 {
+		t.Fatalf("expected 1 section to be added, got %d", len(sections))
+	}
+	body := sections[0].Body
+	if strings.Contains(body, "/photo.jpg") {
+		t.Errorf("expected the remote image reference to be rewritten, got body %q", body)
+	}
+	if strings.Contains(body, "data:image/png") {
+		t.Errorf("expected the data URI to be rewritten to a local file, got body %q", body)
+	}
+	if !strings.Contains(body, srv.URL+"/other-page.html") {
+		t.Errorf("expected the relative link to be resolved against the base URL, got body %q", body)
+	}
+}
+
+// TestImportStripsQueryStringFromImageExtension guards against an
+// image-proxy URL's query string (e.g. "?w=600&h=400") being folded into
+// the generated local filename's extension.
+func TestImportStripsQueryStringFromImageExtension(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("fake jpeg bytes"))
+	}))
+	defer srv.Close()
+
+	html := `<html><body><img src="/photo.jpg?w=600&amp;h=400" alt="a photo"></body></html>`
+
+	e := epub.NewEpub("Article")
+	if _, err := Import(e, html, srv.URL, "Article", nil); err != nil {
+		t.Fatalf("unexpected error from Import: %s", err)
+	}
+
+	images := e.Images()
+	if len(images) != 1 {
+		t.Fatalf("expected 1 image to be added, got %d", len(images))
+	}
+	if images[0].Filename != "htmlimport0001.jpg" {
+		t.Errorf("expected filename %q, got %q", "htmlimport0001.jpg", images[0].Filename)
+	}
+	if images[0].MediaType != "image/jpeg" {
+		t.Errorf("expected media type %q, got %q", "image/jpeg", images[0].MediaType)
+	}
+}
+
+// TestImportWithMultipleStylesheetsLinksTheFirstDeterministically guards
+// against picking a stylesheet by ranging over the (unordered) stylesheets
+// map: repeated imports of the same page must link the same stylesheet, the
+// first one encountered in document order, every time.
+func TestImportWithMultipleStylesheetsLinksTheFirstDeterministically(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte("body { color: red; }"))
+	}))
+	defer srv.Close()
+
+	html := `<html><head>
+<link rel="stylesheet" href="/first.css">
+<link rel="stylesheet" href="/second.css">
+<link rel="stylesheet" href="/third.css">
+</head><body>
+<p>Hello</p>
+</body></html>`
+
+	for i := 0; i < 5; i++ {
+		e := epub.NewEpub("Article")
+		if _, err := Import(e, html, srv.URL, "Article", nil); err != nil {
+			t.Fatalf("unexpected error from Import: %s", err)
+		}
+
+		var buf bytes.Buffer
+		if _, err := e.WriteTo(&buf); err != nil {
+			t.Fatalf("unexpected error from WriteTo: %s", err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("unexpected error reading EPUB as a zip archive: %s", err)
+		}
+
+		var sectionFound bool
+		for _, f := range zr.File {
+			if !strings.HasSuffix(f.Name, "section0001.xhtml") {
+				continue
+			}
+			sectionFound = true
+
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("unexpected error opening %q: %s", f.Name, err)
+			}
+			var contents bytes.Buffer
+			if _, err := contents.ReadFrom(rc); err != nil {
+				t.Fatalf("unexpected error reading %q: %s", f.Name, err)
+			}
+			rc.Close()
+
+			links := strings.Count(contents.String(), "<link rel=\"stylesheet\"")
+			if links != 1 {
+				t.Errorf("run %d: expected exactly 1 stylesheet link, got %d in %s", i, links, contents.String())
+			}
+			if !strings.Contains(contents.String(), "htmlimport0001.css") {
+				t.Errorf("run %d: expected the section to link the first stylesheet encountered, got %s", i, contents.String())
+			}
+		}
+		if !sectionFound {
+			t.Fatalf("run %d: expected a section0001.xhtml entry in the EPUB", i)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package htmlimport
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// inlineAll adds every fetched (or decoded) resource to the target Epub
+// under the filename it was assigned during rewrite.
+func (imp *importer) inlineAll() error {
+	for _, job := range uniqueJobs(imp.images) {
+		mediaType := job.mediaType
+		if mediaType == "" {
+			mediaType = mediaTypeForExtension(job.localFilename)
+		}
+
+		if _, err := imp.epub.AddImageFromReader(bytes.NewReader(job.content), job.localFilename, mediaType); err != nil {
+			return fmt.Errorf("error adding image %q: %w", job.localFilename, err)
+		}
+	}
+
+	for _, job := range uniqueJobs(imp.stylesheets) {
+		if _, err := imp.epub.AddCSSFromReader(bytes.NewReader(job.content), job.localFilename); err != nil {
+			return fmt.Errorf("error adding stylesheet %q: %w", job.localFilename, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package htmlimport
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// extensionOfURL returns the file extension a resolved resource URL should
+// be given inside the EPUB, looking only at the URL's path so a query
+// string (ubiquitous on CDN/image-proxy URLs, e.g. "photo.jpg?w=600&h=400")
+// doesn't get folded into the generated filename's extension.
+func extensionOfURL(resolved string) string {
+	u, err := url.Parse(resolved)
+	if err != nil {
+		return filepath.Ext(resolved)
+	}
+	return filepath.Ext(u.Path)
+}
+
+// extensionForMediaType maps a handful of common image MIME types to a file
+// extension, for naming resources (such as decoded data: URIs) that don't
+// come with a filename of their own.
+func extensionForMediaType(mediaType string) string {
+	switch mediaType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ""
+	}
+}
+
+// mediaTypeForExtension is the inverse of extensionForMediaType, used as a
+// fallback when a fetched resource's Content-Type header is missing.
+func mediaTypeForExtension(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gif":
+		return "image/gif"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".svg":
+		return "image/svg+xml"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "application/octet-stream"
+	}
+}
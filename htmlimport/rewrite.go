@@ -0,0 +1,197 @@
+package htmlimport
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// rewrite walks the parsed document, registering every image and
+// stylesheet it references for download and rewriting the corresponding
+// attributes to point at the local filenames those resources will be given
+// inside the EPUB once fetched.
+func (imp *importer) rewrite(n *html.Node) {
+	if n.Type == html.ElementNode {
+		switch n.Data {
+		case "img":
+			imp.rewriteImg(n)
+		case "link":
+			imp.rewriteLink(n)
+		case "a":
+			imp.rewriteAnchor(n)
+		case "source":
+			imp.rewriteSource(n)
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		imp.rewrite(c)
+	}
+}
+
+func (imp *importer) rewriteImg(n *html.Node) {
+	src := attr(n, "src")
+	if srcset := attr(n, "srcset"); srcset != "" {
+		if best := bestSrcsetCandidate(srcset); best != "" {
+			src = best
+		}
+		removeAttr(n, "srcset")
+	}
+	if src == "" {
+		return
+	}
+
+	if filename := imp.registerImage(src); filename != "" {
+		setAttr(n, "src", path.Join("..", "images", filename))
+	}
+}
+
+// rewriteSource handles <source srcset="..."> inside a <picture>, picking
+// the best candidate the same way an <img srcset> would and replacing the
+// srcset with that single candidate's local filename.
+func (imp *importer) rewriteSource(n *html.Node) {
+	srcset := attr(n, "srcset")
+	if srcset == "" {
+		return
+	}
+
+	best := bestSrcsetCandidate(srcset)
+	if best == "" {
+		return
+	}
+
+	if filename := imp.registerImage(best); filename != "" {
+		setAttr(n, "srcset", path.Join("..", "images", filename))
+	}
+}
+
+func (imp *importer) rewriteLink(n *html.Node) {
+	if attr(n, "rel") != "stylesheet" {
+		return
+	}
+
+	href := attr(n, "href")
+	if href == "" {
+		return
+	}
+
+	if filename := imp.registerStylesheet(href); filename != "" {
+		setAttr(n, "href", path.Join("..", "css", filename))
+	}
+}
+
+// rewriteAnchor resolves a relative link against the page's base URL, since
+// a relative href that made sense on the original page is meaningless once
+// embedded in an EPUB section.
+func (imp *importer) rewriteAnchor(n *html.Node) {
+	href := attr(n, "href")
+	if href == "" || strings.HasPrefix(href, "#") {
+		return
+	}
+
+	resolved, err := imp.resolve(href)
+	if err != nil {
+		return
+	}
+	setAttr(n, "href", resolved)
+}
+
+// registerImage records src (a data: URI, or an absolute/relative URL) as an
+// image to fetch, deduplicating repeated references to the same source, and
+// returns the local filename it's been assigned.
+func (imp *importer) registerImage(src string) string {
+	if job, ok := imp.images[src]; ok {
+		return job.localFilename
+	}
+
+	if strings.HasPrefix(src, "data:") {
+		mediaType, content, err := parseDataURI(src)
+		if err != nil {
+			return ""
+		}
+
+		imp.imageCount++
+		filename := fmt.Sprintf("htmlimport%04d%s", imp.imageCount, extensionForMediaType(mediaType))
+		imp.images[src] = &resourceJob{source: src, localFilename: filename, mediaType: mediaType, content: content}
+		return filename
+	}
+
+	resolved, err := imp.resolve(src)
+	if err != nil {
+		return ""
+	}
+	if job, ok := imp.images[resolved]; ok {
+		imp.images[src] = job
+		return job.localFilename
+	}
+
+	imp.imageCount++
+	filename := fmt.Sprintf("htmlimport%04d%s", imp.imageCount, extensionOfURL(resolved))
+	job := &resourceJob{source: resolved, localFilename: filename}
+	imp.images[src] = job
+	imp.images[resolved] = job
+
+	return filename
+}
+
+func (imp *importer) registerStylesheet(href string) string {
+	resolved, err := imp.resolve(href)
+	if err != nil {
+		return ""
+	}
+	if job, ok := imp.stylesheets[resolved]; ok {
+		return job.localFilename
+	}
+
+	imp.cssCount++
+	filename := fmt.Sprintf("htmlimport%04d.css", imp.cssCount)
+	job := &resourceJob{source: resolved, localFilename: filename}
+	imp.stylesheets[resolved] = job
+	if imp.firstStylesheet == nil {
+		imp.firstStylesheet = job
+	}
+
+	return filename
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key string, val string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+func removeAttr(n *html.Node, key string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return
+		}
+	}
+}
+
+func findNode(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findNode(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package htmlimport
+
+import (
+	"strconv"
+	"strings"
+)
+
+type srcsetCandidate struct {
+	url     string
+	width   float64
+	density float64
+}
+
+// bestSrcsetCandidate parses a srcset attribute value and returns the URL of
+// the best candidate: the one with the largest width descriptor (e.g.
+// "800w"), or failing that the largest pixel-density descriptor (e.g.
+// "2x"), or failing that the last candidate listed, which is the browser
+// fallback when no descriptors are present.
+func bestSrcsetCandidate(srcset string) string {
+	var candidates []srcsetCandidate
+	for _, c := range strings.Split(srcset, ",") {
+		fields := strings.Fields(strings.TrimSpace(c))
+		if len(fields) == 0 {
+			continue
+		}
+
+		cand := srcsetCandidate{url: fields[0]}
+		if len(fields) >= 2 {
+			descriptor := fields[1]
+			switch {
+			case strings.HasSuffix(descriptor, "w"):
+				if w, err := strconv.ParseFloat(strings.TrimSuffix(descriptor, "w"), 64); err == nil {
+					cand.width = w
+				}
+			case strings.HasSuffix(descriptor, "x"):
+				if d, err := strconv.ParseFloat(strings.TrimSuffix(descriptor, "x"), 64); err == nil {
+					cand.density = d
+				}
+			}
+		}
+		candidates = append(candidates, cand)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	best := candidates[len(candidates)-1]
+	for _, c := range candidates {
+		if c.width > best.width || (c.width == best.width && c.density > best.density) {
+			best = c
+		}
+	}
+
+	return best.url
+}
@@ -0,0 +1,24 @@
+package htmlimport
+
+import "testing"
+
+func TestBestSrcsetCandidateWidthDescriptor(t *testing.T) {
+	got := bestSrcsetCandidate("small.jpg 480w, medium.jpg 800w, large.jpg 1600w")
+	if got != "large.jpg" {
+		t.Errorf("expected the widest candidate %q, got %q", "large.jpg", got)
+	}
+}
+
+func TestBestSrcsetCandidateDensityDescriptor(t *testing.T) {
+	got := bestSrcsetCandidate("icon.png 1x, icon@2x.png 2x")
+	if got != "icon@2x.png" {
+		t.Errorf("expected the densest candidate %q, got %q", "icon@2x.png", got)
+	}
+}
+
+func TestBestSrcsetCandidateNoDescriptors(t *testing.T) {
+	got := bestSrcsetCandidate("only.jpg")
+	if got != "only.jpg" {
+		t.Errorf("expected the only candidate %q, got %q", "only.jpg", got)
+	}
+}
@@ -0,0 +1,135 @@
+package epub
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// epubImage is an image that's been added to the EPUB's manifest.
+type epubImage struct {
+	id         string
+	filename   string
+	mediaType  string
+	properties string
+	content    []byte
+}
+
+// AddImage adds an image to the EPUB, either from a local file path or an
+// http(s) URL, and returns the filename it was given inside the EPUB so it
+// can be referenced from section content (e.g. in an <img src="..."> tag).
+// If imageFilename is empty, one is generated automatically.
+func (e *Epub) AddImage(source string, imageFilename string) (string, error) {
+	content, err := fetchResource(source)
+	if err != nil {
+		return "", fmt.Errorf("error adding image from %q: %w", source, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if imageFilename == "" {
+		imageFilename = fmt.Sprintf(imageFileFormat, len(e.images)+1, extensionOf(source))
+	}
+
+	return e.addImageContentLocked(content, imageFilename, mediaTypeForExtension(imageFilename))
+}
+
+// AddImageFromReader adds an image to the EPUB from raw content rather than
+// a path or URL, using mediaType (e.g. "image/png") to identify its format
+// since a Reader carries no filename to infer it from. It's meant for
+// callers that have already fetched or generated the image bytes
+// themselves, such as the htmlimport subsystem decoding a data: URI. If
+// imageFilename is empty, one is generated automatically.
+func (e *Epub) AddImageFromReader(r io.Reader, imageFilename string, mediaType string) (string, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("error reading image content: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if imageFilename == "" {
+		imageFilename = fmt.Sprintf(imageFileFormat, len(e.images)+1, extensionForMediaType(mediaType))
+	}
+
+	return e.addImageContentLocked(content, imageFilename, mediaType)
+}
+
+// ImageHandle refers to an image added with AddImageAsync whose content may
+// still be downloading in the background.
+type ImageHandle struct {
+	// Filename is assigned immediately and is safe to reference from
+	// section content right away, even while the download is still in
+	// flight.
+	Filename string
+
+	done chan struct{}
+	err  error
+}
+
+// Wait blocks until the image's download has finished, returning any error
+// it encountered. Write and WriteTo already wait for every outstanding
+// AddImageAsync/AddCSSAsync call before rendering the EPUB, so callers only
+// need Wait to check for errors early or to block without writing yet.
+func (h *ImageHandle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+// AddImageAsync behaves like AddImage, but fetches source in the
+// background, dispatched to a worker pool sized by SetDownloadConcurrency
+// (4 slots by default), rather than blocking the caller until the download
+// completes. This matters for callers adding many images to the same EPUB,
+// such as an article importer pulling in every image on a page at once.
+func (e *Epub) AddImageAsync(source string, imageFilename string) *ImageHandle {
+	e.mu.Lock()
+	if imageFilename == "" {
+		imageFilename = fmt.Sprintf(imageFileFormat, len(e.images)+1, extensionOf(source))
+	}
+	img := &epubImage{
+		id:        fmt.Sprintf("image%04d", len(e.images)+1),
+		filename:  imageFilename,
+		mediaType: mediaTypeForExtension(imageFilename),
+	}
+	e.images = append(e.images, img)
+	e.mu.Unlock()
+
+	h := &ImageHandle{Filename: imageFilename, done: make(chan struct{})}
+
+	e.pending.Add(1)
+	go func() {
+		defer e.pending.Done()
+		defer close(h.done)
+
+		sem := e.acquireDownloadSlot()
+		defer e.releaseDownloadSlot(sem)
+
+		content, err := fetchResource(source)
+		if err != nil {
+			h.err = fmt.Errorf("error adding image from %q: %w", source, err)
+			e.recordAsyncErr(h.err)
+			return
+		}
+
+		e.mu.Lock()
+		img.content = content
+		e.mu.Unlock()
+	}()
+
+	return h
+}
+
+// addImageContentLocked appends a fully-loaded image to the manifest. The
+// caller must hold e.mu.
+func (e *Epub) addImageContentLocked(content []byte, imageFilename string, mediaType string) (string, error) {
+	e.images = append(e.images, &epubImage{
+		id:        fmt.Sprintf("image%04d", len(e.images)+1),
+		filename:  imageFilename,
+		mediaType: mediaType,
+		content:   content,
+	})
+
+	return imageFilename, nil
+}
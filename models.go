@@ -0,0 +1,143 @@
+package epub
+
+import "encoding/xml"
+
+// opfPackage mirrors the root <package> element of an EPUB 3 package
+// document (package.opf). It's used both to marshal a new package document
+// on Write and to unmarshal an existing one on Open, so the two stay in
+// sync by construction.
+type opfPackage struct {
+	XMLName          xml.Name    `xml:"http://www.idpf.org/2007/opf package"`
+	Version          string      `xml:"version,attr"`
+	UniqueIdentifier string      `xml:"unique-identifier,attr"`
+	Metadata         opfMetadata `xml:"metadata"`
+	Manifest         opfManifest `xml:"manifest"`
+	Spine            opfSpine    `xml:"spine"`
+}
+
+type opfMetadata struct {
+	XMLNSDC    string        `xml:"xmlns:dc,attr"`
+	Identifier opfIdentifier `xml:"dc:identifier"`
+	Title      string        `xml:"dc:title"`
+	Language   string        `xml:"dc:language"`
+	Creators   []opfCreator  `xml:"dc:creator,omitempty"`
+	Meta       []opfMeta     `xml:"meta,omitempty"`
+}
+
+// UnmarshalXML decodes <metadata> by matching child elements on their local
+// name only (identifier, title, language, creator, meta), ignoring whatever
+// namespace URI the "dc" prefix resolves to. encoding/xml's default decoding
+// can't do this: it resolves dc:identifier etc. to the Dublin Core namespace
+// and local name "identifier", which never matches a literal "dc:identifier"
+// struct tag, so every Dublin Core field would otherwise decode as zero.
+func (m *opfMetadata) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" && attr.Name.Local == "dc" {
+			m.XMLNSDC = attr.Value
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "identifier":
+				var v opfIdentifier
+				if err := d.DecodeElement(&v, &t); err != nil {
+					return err
+				}
+				m.Identifier = v
+			case "title":
+				var v string
+				if err := d.DecodeElement(&v, &t); err != nil {
+					return err
+				}
+				m.Title = v
+			case "language":
+				var v string
+				if err := d.DecodeElement(&v, &t); err != nil {
+					return err
+				}
+				m.Language = v
+			case "creator":
+				var v opfCreator
+				if err := d.DecodeElement(&v, &t); err != nil {
+					return err
+				}
+				m.Creators = append(m.Creators, v)
+			case "meta":
+				var v opfMeta
+				if err := d.DecodeElement(&v, &t); err != nil {
+					return err
+				}
+				m.Meta = append(m.Meta, v)
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type opfIdentifier struct {
+	ID       string `xml:"id,attr"`
+	CharData string `xml:",chardata"`
+}
+
+type opfCreator struct {
+	ID       string `xml:"id,attr,omitempty"`
+	CharData string `xml:",chardata"`
+}
+
+// opfMeta covers both the EPUB 3 <meta property="..."> form and the legacy
+// EPUB 2 <meta name="..." content="..."/> form, since both share the same
+// element name and only differ in which attributes are populated.
+type opfMeta struct {
+	Name     string `xml:"name,attr,omitempty"`
+	Property string `xml:"property,attr,omitempty"`
+	Refines  string `xml:"refines,attr,omitempty"`
+	Content  string `xml:"content,attr,omitempty"`
+	CharData string `xml:",chardata"`
+}
+
+type opfManifest struct {
+	Items []opfManifestItem `xml:"item"`
+}
+
+type opfManifestItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr,omitempty"`
+}
+
+type opfSpine struct {
+	Toc      string       `xml:"toc,attr,omitempty"`
+	Itemrefs []opfItemref `xml:"itemref"`
+}
+
+type opfItemref struct {
+	IDRef  string `xml:"idref,attr"`
+	Linear string `xml:"linear,attr,omitempty"`
+}
+
+// ocfContainer mirrors META-INF/container.xml, which the OCF spec uses to
+// point a reader at the package document.
+type ocfContainer struct {
+	XMLName   xml.Name      `xml:"urn:oasis:names:tc:opendocument:xmlns:container container"`
+	Version   string        `xml:"version,attr"`
+	Rootfiles []ocfRootfile `xml:"rootfiles>rootfile"`
+}
+
+type ocfRootfile struct {
+	FullPath  string `xml:"full-path,attr"`
+	MediaType string `xml:"media-type,attr"`
+}
@@ -0,0 +1,186 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Open reads an existing .epub file from path and returns it as an *Epub,
+// so its metadata, sections, and resources can be inspected, and so it can
+// be written back out unchanged (or modified first) with Write or WriteTo.
+// Open(path) followed by Write(path2) produces a functionally equivalent
+// EPUB: the mimetype entry stays first and stored. Write always places the
+// package document at EPUB/package.opf, with sections, images, stylesheets
+// and fonts in fixed folders beside it, so container.xml points at the same
+// package document path only for an EPUB laid out that way (as every EPUB
+// this package writes is); any other layout is rewritten into this one.
+func Open(path string) (*Epub, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %q: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error statting %q: %w", path, err)
+	}
+
+	return NewReader(f, info.Size())
+}
+
+// NewReader parses an EPUB from r, which must support random access (as
+// *os.File and bytes.Reader do), given its total size in bytes. This mirrors
+// zip.NewReader and lets callers parse an EPUB held in memory, or streamed
+// from an HTTP response body read into a buffer, without a temp file.
+func NewReader(r io.ReaderAt, size int64) (*Epub, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error opening EPUB as a zip archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	containerBytes, err := readZipFile(files, metaInfFolderName+"/"+containerFilename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", containerFilename, err)
+	}
+
+	var container ocfContainer
+	if err := xml.Unmarshal(containerBytes, &container); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", containerFilename, err)
+	}
+	if len(container.Rootfiles) == 0 {
+		return nil, fmt.Errorf("%s declares no rootfile", containerFilename)
+	}
+	pkgPath := container.Rootfiles[0].FullPath
+
+	pkgBytes, err := readZipFile(files, pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading package document %q: %w", pkgPath, err)
+	}
+
+	var pkg opfPackage
+	if err := xml.Unmarshal(pkgBytes, &pkg); err != nil {
+		return nil, fmt.Errorf("error parsing package document %q: %w", pkgPath, err)
+	}
+
+	return epubFromPackageDocument(&pkg, pkgPath, files)
+}
+
+// epubFromPackageDocument builds an *Epub from a parsed package document,
+// resolving every manifest item's href against the archive so resource
+// content is loaded up front, the same way AddImage/AddCSS/AddFont leave it.
+func epubFromPackageDocument(pkg *opfPackage, pkgPath string, files map[string]*zip.File) (*Epub, error) {
+	pkgDir := path.Dir(pkgPath)
+
+	e := &Epub{
+		identifier: pkg.Metadata.Identifier.CharData,
+		lang:       pkg.Metadata.Language,
+		pkgID:      pkg.UniqueIdentifier,
+		title:      pkg.Metadata.Title,
+	}
+	for _, c := range pkg.Metadata.Creators {
+		e.authors = append(e.authors, c.CharData)
+	}
+
+	itemsByID := make(map[string]opfManifestItem, len(pkg.Manifest.Items))
+	for _, item := range pkg.Manifest.Items {
+		itemsByID[item.ID] = item
+
+		if item.ID == "nav" || item.ID == "ncx" {
+			// The nav document and ncx are regenerated from the section
+			// list on Write, not kept verbatim.
+			continue
+		}
+
+		href := path.Join(pkgDir, item.Href)
+		switch {
+		case strings.HasPrefix(item.MediaType, "image/"):
+			content, err := readZipFile(files, href)
+			if err != nil {
+				return nil, fmt.Errorf("error reading image %q: %w", href, err)
+			}
+			e.images = append(e.images, &epubImage{
+				id:         item.ID,
+				filename:   path.Base(item.Href),
+				mediaType:  item.MediaType,
+				properties: item.Properties,
+				content:    content,
+			})
+			if strings.Contains(item.Properties, "cover-image") {
+				e.coverImageFilename = path.Base(item.Href)
+			}
+
+		case item.MediaType == "text/css":
+			content, err := readZipFile(files, href)
+			if err != nil {
+				return nil, fmt.Errorf("error reading stylesheet %q: %w", href, err)
+			}
+			e.css = append(e.css, &epubCSS{id: item.ID, filename: path.Base(item.Href), content: content})
+
+		case strings.HasPrefix(item.MediaType, "font/"):
+			content, err := readZipFile(files, href)
+			if err != nil {
+				return nil, fmt.Errorf("error reading font %q: %w", href, err)
+			}
+			e.fonts = append(e.fonts, &epubFont{id: item.ID, filename: path.Base(item.Href), content: content})
+		}
+	}
+
+	for _, ref := range pkg.Spine.Itemrefs {
+		item, ok := itemsByID[ref.IDRef]
+		if !ok {
+			continue
+		}
+
+		href := path.Join(pkgDir, item.Href)
+		content, err := readZipFile(files, href)
+		if err != nil {
+			return nil, fmt.Errorf("error reading section %q: %w", href, err)
+		}
+
+		e.sections = append(e.sections, &epubSection{
+			id:       item.ID,
+			filename: path.Base(item.Href),
+			title:    sectionTitleFromXHTML(content),
+			body:     sectionBodyFromXHTML(content),
+			linear:   ref.Linear,
+		})
+	}
+
+	return e, nil
+}
+
+func readZipFile(files map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("file %q not found in archive", name)
+	}
+
+	return readZipEntry(f)
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,44 @@
+package epub
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewReaderRecoversMetadata(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+	e.SetAuthor(testEpubAuthor)
+	e.SetLanguage("fr")
+	e.SetIdentifier("urn:isbn:9780131103627")
+	if _, err := e.AddSection("<p>Chapter 1</p>", "Chapter 1", "", ""); err != nil {
+		t.Fatalf("unexpected error adding section: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error from WriteTo: %s", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error from NewReader: %s", err)
+	}
+
+	if r.Title() != testEpubTitle {
+		t.Errorf("expected title %q, got %q", testEpubTitle, r.Title())
+	}
+	if r.Author() != testEpubAuthor {
+		t.Errorf("expected author %q, got %q", testEpubAuthor, r.Author())
+	}
+	if r.Language() != "fr" {
+		t.Errorf("expected language %q, got %q", "fr", r.Language())
+	}
+	if r.Identifier() != "urn:isbn:9780131103627" {
+		t.Errorf("expected identifier %q, got %q", "urn:isbn:9780131103627", r.Identifier())
+	}
+
+	sections := r.Sections()
+	if len(sections) != 1 || sections[0].Title != "Chapter 1" {
+		t.Errorf("expected a single section titled %q, got %+v", "Chapter 1", sections)
+	}
+}
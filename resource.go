@@ -0,0 +1,85 @@
+package epub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// fetchResource reads the contents of source, which may be either a path to
+// a local file or an http(s) URL, so that images, CSS, and fonts can be
+// added to an Epub either way.
+func fetchResource(source string) ([]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := http.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading %q: %w", source, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error downloading %q: got HTTP status %s", source, resp.Status)
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body for %q: %w", source, err)
+		}
+
+		return body, nil
+	}
+
+	content, err := ioutil.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", source, err)
+	}
+
+	return content, nil
+}
+
+// extensionOf returns the file extension source should be given inside the
+// EPUB. For an http(s) URL, it strips the query string and fragment first
+// (e.g. an image-proxy URL like "photo.jpg?w=600&h=400" must yield ".jpg",
+// not ".jpg?w=600&h=400") by looking only at the URL's path; for a local
+// file path it falls back to filepath.Ext directly.
+func extensionOf(source string) string {
+	if u, err := url.Parse(source); err == nil && u.Scheme != "" {
+		return filepath.Ext(u.Path)
+	}
+	return filepath.Ext(source)
+}
+
+// mediaTypeForExtension returns the MIME type to use in the OPF manifest for
+// a given filename or source URL, falling back to a generic binary type for
+// extensions it doesn't recognize.
+func mediaTypeForExtension(filename string) string {
+	switch strings.ToLower(extensionOf(filename)) {
+	case ".gif":
+		return "image/gif"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".svg":
+		return "image/svg+xml"
+	case ".webp":
+		return "image/webp"
+	case ".css":
+		return "text/css"
+	case ".otf":
+		return "font/otf"
+	case ".ttf":
+		return "font/ttf"
+	case ".woff":
+		return "font/woff"
+	case ".woff2":
+		return "font/woff2"
+	case ".xhtml", ".html", ".htm":
+		return "application/xhtml+xml"
+	default:
+		return "application/octet-stream"
+	}
+}
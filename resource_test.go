@@ -0,0 +1,28 @@
+package epub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAddImageStripsQueryStringFromExtension(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fake image bytes"))
+	}))
+	defer srv.Close()
+
+	e := NewEpub(testEpubTitle)
+	filename, err := e.AddImage(srv.URL+"/photo.jpg?w=600&h=400", "")
+	if err != nil {
+		t.Fatalf("unexpected error from AddImage: %s", err)
+	}
+	if filename != "image0001.jpg" {
+		t.Errorf("expected filename %q, got %q", "image0001.jpg", filename)
+	}
+
+	images := e.Images()
+	if len(images) != 1 || images[0].MediaType != "image/jpeg" {
+		t.Errorf("expected a single image/jpeg image, got %+v", images)
+	}
+}
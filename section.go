@@ -0,0 +1,41 @@
+package epub
+
+import "fmt"
+
+// epubSection is a section of body content that's been added to the EPUB's
+// spine and table of contents.
+type epubSection struct {
+	id          string
+	filename    string
+	title       string
+	body        string
+	cssFilename string
+	linear      string
+}
+
+// AddSection adds a new section (e.g. a chapter) to the EPUB, in the order
+// it was added. body is the content that goes inside the XHTML <body> tag;
+// title is used in the table of contents. If internalFilename is empty, one
+// is generated automatically. cssFilename, if non-empty, must refer to a
+// stylesheet previously added with AddCSS, and is linked from the section's
+// <head>. AddSection returns the filename the section was given inside the
+// EPUB.
+func (e *Epub) AddSection(body string, title string, internalFilename string, cssFilename string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if internalFilename == "" {
+		internalFilename = fmt.Sprintf(sectionFileFormat, len(e.sections)+1)
+	}
+
+	s := &epubSection{
+		id:          fmt.Sprintf("section%04d", len(e.sections)+1),
+		filename:    internalFilename,
+		title:       title,
+		body:        body,
+		cssFilename: cssFilename,
+	}
+	e.sections = append(e.sections, s)
+
+	return internalFilename, nil
+}
@@ -0,0 +1,27 @@
+package epub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// navEntries renders the <li> entries used by the nav document's <ol>; the
+// ncx's <navMap> entries come from ncxNavPoints.
+func (e *Epub) navEntries() string {
+	var b strings.Builder
+	for _, s := range e.sections {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", escapeXML(sectionsFolderName+"/"+s.filename), escapeXML(s.title))
+	}
+
+	return b.String()
+}
+
+func (e *Epub) ncxNavPoints() string {
+	var b strings.Builder
+	for i, s := range e.sections {
+		fmt.Fprintf(&b, "<navPoint id=\"%s\" playOrder=\"%d\">\n<navLabel><text>%s</text></navLabel>\n<content src=\"%s\"/>\n</navPoint>\n",
+			s.id, i+1, escapeXML(s.title), escapeXML(sectionsFolderName+"/"+s.filename))
+	}
+
+	return b.String()
+}
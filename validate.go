@@ -0,0 +1,202 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Severity classifies how serious a ValidationIssue is.
+type Severity string
+
+const (
+	// SeverityError marks a violation likely to make some readers reject
+	// the EPUB outright.
+	SeverityError Severity = "error"
+	// SeverityWarning marks something that's technically permitted but
+	// likely to cause trouble, such as an orphaned file.
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is a single structural conformance problem found by
+// Validate or ValidateFile.
+type ValidationIssue struct {
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Code, i.Message)
+}
+
+var iso8601Re = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)
+
+// Validate renders the EPUB the same way Write would, then runs the same
+// structural conformance checks as ValidateFile against the result,
+// without touching disk.
+func (e *Epub) Validate() []ValidationIssue {
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		return []ValidationIssue{{Severity: SeverityError, Code: "write-failed", Message: err.Error()}}
+	}
+
+	return validateZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+// ValidateFile runs the same structural conformance checks as Validate
+// against an EPUB already written to path. It exists to catch the class of
+// bug where a hand-assembled or re-zipped EPUB silently fails to open in
+// some readers because, say, the mimetype entry ended up compressed or
+// container.xml was misplaced.
+func ValidateFile(path string) ([]ValidationIssue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %q: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error statting %q: %w", path, err)
+	}
+
+	return validateZip(f, info.Size()), nil
+}
+
+func validateZip(r io.ReaderAt, size int64) []ValidationIssue {
+	var issues []ValidationIssue
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "not-a-zip", Message: err.Error()})
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	if len(zr.File) == 0 || zr.File[0].Name != mimetypeFilename {
+		issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "mimetype-not-first",
+			Message: fmt.Sprintf("%s must be the first entry in the archive", mimetypeFilename)})
+	} else if zr.File[0].Method != zip.Store {
+		issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "mimetype-compressed",
+			Message: fmt.Sprintf("%s must be stored uncompressed", mimetypeFilename)})
+	}
+
+	containerFile, ok := files[metaInfFolderName+"/"+containerFilename]
+	if !ok {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "missing-container",
+			Message: fmt.Sprintf("%s/%s not found", metaInfFolderName, containerFilename)})
+	}
+
+	containerBytes, err := readZipEntry(containerFile)
+	if err != nil {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "unreadable-container", Message: err.Error()})
+	}
+
+	var container ocfContainer
+	if err := xml.Unmarshal(containerBytes, &container); err != nil {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "malformed-container", Message: err.Error()})
+	}
+	if len(container.Rootfiles) == 0 {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "no-rootfile", Message: "container.xml declares no rootfile"})
+	}
+	pkgPath := container.Rootfiles[0].FullPath
+
+	pkgFile, ok := files[pkgPath]
+	if !ok {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "rootfile-not-found",
+			Message: fmt.Sprintf("rootfile %q does not exist in the archive", pkgPath)})
+	}
+
+	pkgBytes, err := readZipEntry(pkgFile)
+	if err != nil {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "unreadable-package", Message: err.Error()})
+	}
+
+	var pkg opfPackage
+	if err := xml.Unmarshal(pkgBytes, &pkg); err != nil {
+		return append(issues, ValidationIssue{Severity: SeverityError, Code: "malformed-package", Message: err.Error()})
+	}
+
+	pkgDir := path.Dir(pkgPath)
+	referenced := map[string]bool{
+		mimetypeFilename: true,
+		metaInfFolderName + "/" + containerFilename: true,
+		pkgPath: true,
+	}
+
+	itemsByID := make(map[string]opfManifestItem, len(pkg.Manifest.Items))
+	var navHref string
+	for _, item := range pkg.Manifest.Items {
+		itemsByID[item.ID] = item
+
+		href := path.Join(pkgDir, item.Href)
+		referenced[href] = true
+		if _, ok := files[href]; !ok {
+			issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "manifest-item-missing",
+				Message: fmt.Sprintf("manifest item %q references %q, which does not exist in the archive", item.ID, href)})
+		}
+
+		if strings.Contains(item.Properties, "nav") {
+			navHref = href
+		}
+	}
+
+	if navHref == "" {
+		issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "no-nav",
+			Message: `manifest has no item with properties="nav"`})
+	} else if navFile, ok := files[navHref]; ok {
+		navBytes, err := readZipEntry(navFile)
+		if err != nil {
+			issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "unreadable-nav", Message: err.Error()})
+		} else if !bytes.Contains(navBytes, []byte("<ol")) {
+			issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "nav-missing-ol",
+				Message: "navigation document has no <ol> table of contents"})
+		}
+	}
+
+	for _, ref := range pkg.Spine.Itemrefs {
+		if _, ok := itemsByID[ref.IDRef]; !ok {
+			issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "spine-itemref-missing",
+				Message: fmt.Sprintf("spine itemref %q does not refer to a manifest item", ref.IDRef)})
+		}
+	}
+
+	if pkg.UniqueIdentifier == "" || pkg.UniqueIdentifier != pkg.Metadata.Identifier.ID {
+		issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "unique-identifier-mismatch",
+			Message: fmt.Sprintf("package unique-identifier %q does not match any dc:identifier id", pkg.UniqueIdentifier)})
+	}
+
+	modified := ""
+	for _, m := range pkg.Metadata.Meta {
+		if m.Property == "dcterms:modified" {
+			modified = m.CharData
+		}
+	}
+	switch {
+	case modified == "":
+		issues = append(issues, ValidationIssue{Severity: SeverityWarning, Code: "missing-modified",
+			Message: "metadata has no dcterms:modified entry"})
+	case !iso8601Re.MatchString(modified):
+		issues = append(issues, ValidationIssue{Severity: SeverityError, Code: "invalid-modified",
+			Message: fmt.Sprintf("dcterms:modified %q is not a well-formed ISO-8601 timestamp", modified)})
+	}
+
+	for name := range files {
+		if !referenced[name] {
+			issues = append(issues, ValidationIssue{Severity: SeverityWarning, Code: "orphan-file",
+				Message: fmt.Sprintf("%q is not referenced by the container or package manifest", name)})
+		}
+	}
+
+	return issues
+}
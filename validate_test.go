@@ -0,0 +1,60 @@
+package epub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateCleanEpubHasNoErrors(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+	e.SetAuthor(testEpubAuthor)
+	if _, err := e.AddSection("<p>Hello</p>", "Chapter 1", "", ""); err != nil {
+		t.Fatalf("unexpected error adding section: %s", err)
+	}
+
+	for _, issue := range e.Validate() {
+		if issue.Severity == SeverityError {
+			t.Errorf("unexpected validation error: %s", issue)
+		}
+	}
+}
+
+func TestValidateDoesNotFlagUniqueIdentifierMismatch(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+	e.SetAuthor(testEpubAuthor)
+	if _, err := e.AddSection("<p>Hello</p>", "Chapter 1", "", ""); err != nil {
+		t.Fatalf("unexpected error adding section: %s", err)
+	}
+
+	for _, issue := range e.Validate() {
+		if issue.Code == "unique-identifier-mismatch" {
+			t.Errorf("unexpected unique-identifier-mismatch on an EPUB whose package unique-identifier and dc:identifier id always match: %s", issue)
+		}
+	}
+}
+
+func TestValidateFileRejectsNonEpub(t *testing.T) {
+	dir, err := ioutil.TempDir("", tempDirPrefix)
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "not-an-epub.txt")
+	if err := ioutil.WriteFile(tmp, []byte("not a zip"), 0644); err != nil {
+		t.Fatalf("unexpected error writing test file: %s", err)
+	}
+
+	issues, err := ValidateFile(tmp)
+	if err != nil {
+		t.Fatalf("unexpected error from ValidateFile: %s", err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("expected at least one validation issue for a non-EPUB file")
+	}
+	if issues[0].Code != "not-a-zip" {
+		t.Errorf("expected code %q, got %q", "not-a-zip", issues[0].Code)
+	}
+}
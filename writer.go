@@ -0,0 +1,248 @@
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Write renders the EPUB and writes it as a single file to the given path.
+func (e *Epub) Write(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating output file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	_, err = e.WriteTo(f)
+	return err
+}
+
+// WriteTo renders the EPUB and streams it to w, returning the number of
+// bytes written. Unlike Write, it never touches disk, which makes it
+// suitable for generating an EPUB on demand in an HTTP handler, streaming it
+// straight into an upload, or building one entirely in memory.
+//
+// WriteTo first waits for any outstanding AddImageAsync/AddCSSAsync
+// downloads to finish, so the archive it produces never contains a
+// placeholder filename with no content behind it. If any of those downloads
+// failed, WriteTo returns that error instead of silently writing a broken
+// EPUB, even if the caller never called the returned Handle's Wait().
+func (e *Epub) WriteTo(w io.Writer) (int64, error) {
+	e.pending.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.asyncErr != nil {
+		return 0, fmt.Errorf("error rendering EPUB: %w", e.asyncErr)
+	}
+
+	cw := &countingWriter{w: w}
+	zw := zip.NewWriter(cw)
+
+	if err := e.writeMimetype(zw); err != nil {
+		return cw.n, err
+	}
+	if err := e.writeContainer(zw); err != nil {
+		return cw.n, err
+	}
+	if err := e.writePackage(zw); err != nil {
+		return cw.n, err
+	}
+	if err := e.writeToc(zw); err != nil {
+		return cw.n, err
+	}
+	if err := e.writeResources(zw); err != nil {
+		return cw.n, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return cw.n, fmt.Errorf("error finalizing EPUB archive: %w", err)
+	}
+
+	return cw.n, nil
+}
+
+// countingWriter wraps an io.Writer to track the total number of bytes
+// written to it, since zip.Writer doesn't report that on its own.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// writeMimetype writes the OCF mimetype entry. It must be the very first
+// entry in the archive and must be stored rather than deflated, so that a
+// reader can identify the file type without decompressing anything.
+func (e *Epub) writeMimetype(zw *zip.Writer) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   mimetypeFilename,
+		Method: zip.Store,
+	})
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", mimetypeFilename, err)
+	}
+
+	_, err = w.Write([]byte(mimetypeContents))
+	return err
+}
+
+func (e *Epub) writeContainer(zw *zip.Writer) error {
+	w, err := zw.Create(metaInfFolderName + "/" + containerFilename)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", containerFilename, err)
+	}
+
+	_, err = fmt.Fprintf(w, containerFileTemplate, contentFolderName+"/"+pkgFilename)
+	return err
+}
+
+func (e *Epub) writePackage(zw *zip.Writer) error {
+	out, err := xml.MarshalIndent(e.buildPackageDocument(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling %s: %w", pkgFilename, err)
+	}
+
+	w, err := zw.Create(contentFolderName + "/" + pkgFilename)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", pkgFilename, err)
+	}
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// buildPackageDocument assembles the OPF package document from the EPUB's
+// current metadata and manifest/spine entries.
+func (e *Epub) buildPackageDocument() *opfPackage {
+	meta := []opfMeta{
+		{Property: "dcterms:modified", CharData: time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	if e.coverImageFilename != "" {
+		// EPUB 3 readers identify the cover via the manifest item's
+		// properties="cover-image" attribute, set below. This legacy
+		// EPUB 2 form is kept alongside it for older readers, such as
+		// Calibre, that don't look at manifest properties at all.
+		meta = append(meta, opfMeta{Name: "cover", Content: "cover-image"})
+	}
+
+	var creators []opfCreator
+	for i, author := range e.authors {
+		id := "creator"
+		if i > 0 {
+			id = fmt.Sprintf("creator%d", i+1)
+		}
+		creators = append(creators, opfCreator{ID: id, CharData: author})
+	}
+
+	items := []opfManifestItem{
+		{ID: "nav", Href: tocNavFilename, MediaType: "application/xhtml+xml", Properties: "nav"},
+		{ID: "ncx", Href: tocNcxFilename, MediaType: "application/x-dtbncx+xml"},
+	}
+	var itemrefs []opfItemref
+	for _, s := range e.sections {
+		items = append(items, opfManifestItem{ID: s.id, Href: sectionsFolderName + "/" + s.filename, MediaType: "application/xhtml+xml"})
+		itemrefs = append(itemrefs, opfItemref{IDRef: s.id, Linear: s.linear})
+	}
+	for _, c := range e.css {
+		items = append(items, opfManifestItem{ID: c.id, Href: cssFolderName + "/" + c.filename, MediaType: "text/css"})
+	}
+	for _, img := range e.images {
+		items = append(items, opfManifestItem{ID: img.id, Href: imageFolderName + "/" + img.filename, MediaType: img.mediaType, Properties: img.properties})
+	}
+	for _, fnt := range e.fonts {
+		items = append(items, opfManifestItem{ID: fnt.id, Href: fontFolderName + "/" + fnt.filename, MediaType: mediaTypeForExtension(fnt.filename)})
+	}
+
+	return &opfPackage{
+		Version:          "3.0",
+		UniqueIdentifier: e.pkgID,
+		Metadata: opfMetadata{
+			XMLNSDC:    "http://purl.org/dc/elements/1.1/",
+			Identifier: opfIdentifier{ID: e.pkgID, CharData: e.identifier},
+			Title:      e.title,
+			Language:   e.lang,
+			Creators:   creators,
+			Meta:       meta,
+		},
+		Manifest: opfManifest{Items: items},
+		Spine:    opfSpine{Toc: "ncx", Itemrefs: itemrefs},
+	}
+}
+
+func (e *Epub) writeToc(zw *zip.Writer) error {
+	navW, err := zw.Create(contentFolderName + "/" + tocNavFilename)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", tocNavFilename, err)
+	}
+	if _, err := fmt.Fprintf(navW, tocNavFileTemplate, escapeXML(e.title), e.navEntries()); err != nil {
+		return err
+	}
+
+	ncxW, err := zw.Create(contentFolderName + "/" + tocNcxFilename)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", tocNcxFilename, err)
+	}
+	_, err = fmt.Fprintf(ncxW, tocNcxFileTemplate, escapeXML(e.identifier), escapeXML(e.title), e.ncxNavPoints())
+	return err
+}
+
+func (e *Epub) writeResources(zw *zip.Writer) error {
+	for _, s := range e.sections {
+		w, err := zw.Create(contentFolderName + "/" + sectionsFolderName + "/" + s.filename)
+		if err != nil {
+			return fmt.Errorf("error writing section %q: %w", s.filename, err)
+		}
+
+		head := ""
+		if s.cssFilename != "" {
+			head = fmt.Sprintf("<link rel=\"stylesheet\" type=\"text/css\" href=\"../%s/%s\" />\n", cssFolderName, s.cssFilename)
+		}
+		if _, err := fmt.Fprintf(w, xhtmlFileTemplate, escapeXML(s.title), head, s.body); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range e.css {
+		w, err := zw.Create(contentFolderName + "/" + cssFolderName + "/" + c.filename)
+		if err != nil {
+			return fmt.Errorf("error writing CSS %q: %w", c.filename, err)
+		}
+		if _, err := w.Write(c.content); err != nil {
+			return err
+		}
+	}
+
+	for _, img := range e.images {
+		w, err := zw.Create(contentFolderName + "/" + imageFolderName + "/" + img.filename)
+		if err != nil {
+			return fmt.Errorf("error writing image %q: %w", img.filename, err)
+		}
+		if _, err := w.Write(img.content); err != nil {
+			return err
+		}
+	}
+
+	for _, fnt := range e.fonts {
+		w, err := zw.Create(contentFolderName + "/" + fontFolderName + "/" + fnt.filename)
+		if err != nil {
+			return fmt.Errorf("error writing font %q: %w", fnt.filename, err)
+		}
+		if _, err := w.Write(fnt.content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
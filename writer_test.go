@@ -0,0 +1,121 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEpubWriteTo(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	var buf bytes.Buffer
+	n, err := e.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error from WriteTo: %s", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo reported %d bytes written, but the buffer holds %d", n, buf.Len())
+	}
+	if buf.Len() == 0 {
+		t.Fatal("WriteTo produced no output")
+	}
+}
+
+func TestEpubWriteToMimetypeIsFirstAndUncompressed(t *testing.T) {
+	e := NewEpub(testEpubTitle)
+
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error from WriteTo: %s", err)
+	}
+
+	data := buf.Bytes()
+	if len(data) < 60 {
+		t.Fatalf("archive is too small to contain a local file header: got %d bytes", len(data))
+	}
+
+	// The OCF spec requires the mimetype entry to be the very first file in
+	// the archive, stored rather than deflated and with no extra field, so
+	// a reader can identify an EPUB by inspecting a fixed byte range
+	// without walking the ZIP central directory.
+	window := data[30:60]
+	if !bytes.HasPrefix(window, []byte(mimetypeFilename)) {
+		t.Errorf("expected %q at offset 30, got %q", mimetypeFilename, window)
+	}
+	if !strings.Contains(string(window), mimetypeContents) {
+		t.Errorf("expected uncompressed mimetype contents %q near offset 30, got %q", mimetypeContents, window)
+	}
+}
+
+// TestEpubWriteToEscapesTitlesInNavAndNcx guards against a title containing
+// XML metacharacters (common in real book titles, e.g. "Pride & Prejudice")
+// producing malformed nav.xhtml/toc.ncx/section XHTML.
+func TestEpubWriteToEscapesTitlesInNavAndNcx(t *testing.T) {
+	e := NewEpub(`Pride & Prejudice <1813>`)
+	if _, err := e.AddSection("<p>Chapter 1</p>", `Chapter 1 & "Intro"`, "", ""); err != nil {
+		t.Fatalf("unexpected error adding section: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error from WriteTo: %s", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error reading EPUB as a zip archive: %s", err)
+	}
+
+	for _, name := range []string{
+		contentFolderName + "/" + tocNavFilename,
+		contentFolderName + "/" + tocNcxFilename,
+		contentFolderName + "/" + sectionsFolderName + "/section0001.xhtml",
+	} {
+		f, ok := findZipFile(zr, name)
+		if !ok {
+			t.Fatalf("expected %q in the archive", name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("unexpected error opening %q: %s", name, err)
+		}
+		var contents bytes.Buffer
+		if _, err := contents.ReadFrom(rc); err != nil {
+			t.Fatalf("unexpected error reading %q: %s", name, err)
+		}
+		rc.Close()
+
+		if err := validateWellFormedXML(contents.Bytes()); err != nil {
+			t.Errorf("%q is not well-formed XML: %s\ncontents: %s", name, err, contents.String())
+		}
+	}
+}
+
+// validateWellFormedXML walks every token in content, returning the first
+// syntax error encountered (or nil if it parses cleanly to EOF).
+func validateWellFormedXML(content []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func findZipFile(zr *zip.Reader, name string) (*zip.File, bool) {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,46 @@
+package epub
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"regexp"
+)
+
+var (
+	titleTagRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	bodyTagRe  = regexp.MustCompile(`(?is)<body[^>]*>(.*?)</body>`)
+)
+
+// sectionTitleFromXHTML extracts the <title> content from a rendered
+// section, for callers re-opening an EPUB this package wrote (or any other
+// well-formed XHTML section), with XML character references unescaped.
+func sectionTitleFromXHTML(content []byte) string {
+	m := titleTagRe.FindSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(string(m[1]))
+}
+
+// sectionBodyFromXHTML extracts the contents of the <body> tag from a
+// rendered section.
+func sectionBodyFromXHTML(content []byte) string {
+	m := bodyTagRe.FindSubmatch(content)
+	if m == nil {
+		return string(content)
+	}
+	return string(m[1])
+}
+
+// escapeXML escapes s for safe interpolation into XML/XHTML text content or
+// attribute values (e.g. a user-supplied title containing "&" or "<"), the
+// same way encoding/xml.Marshal would if the value were a struct field
+// instead of a Sprintf argument.
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	if err := xml.EscapeText(&b, []byte(s)); err != nil {
+		return s
+	}
+	return b.String()
+}